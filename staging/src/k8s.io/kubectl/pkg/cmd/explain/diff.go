@@ -0,0 +1,137 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package explain
+
+import (
+	"fmt"
+
+	"k8s.io/kubectl/pkg/explain"
+)
+
+// runDiff prints a line-level diff between the schema subtrees named by argA
+// and argB. This is useful for reasoning about API version migrations (e.g.
+// deployment.spec in apps/v1 vs extensions/v1beta1 via --api-version) and
+// for comparing CRDs of the same Kind across groups.
+func (o *ExplainOptions) runDiff(argA, argB string) error {
+	if o.EnableOpenAPIV3 {
+		return fmt.Errorf("--diff is not yet supported with the OpenAPI v3 renderer")
+	}
+
+	linesA, err := o.flattenArg(argA)
+	if err != nil {
+		return err
+	}
+	linesB, err := o.flattenArg(argB)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(o.Out, "--- %s\n+++ %s\n", argA, argB)
+	for _, line := range lineDiff(linesA, linesB) {
+		fmt.Fprintln(o.Out, line)
+	}
+	return nil
+}
+
+// flattenArg resolves arg to a resource and returns its schema subtree
+// flattened to one "path -> (type, required, description)" line per field.
+func (o *ExplainOptions) flattenArg(arg string) ([]string, error) {
+	fullySpecifiedGVR, fieldsPath, err := o.resolve(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	_, modelSchema, err := o.lookupSchema(fullySpecifiedGVR)
+	if err != nil {
+		return nil, err
+	}
+
+	flattened, err := explain.Flatten(fieldsPath, modelSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]string, len(flattened))
+	for i, f := range flattened {
+		lines[i] = f.String()
+	}
+	return lines, nil
+}
+
+// lineDiff produces a unified-diff-style listing of the differences between
+// a and b using a standard LCS-based line diff: unchanged lines are kept,
+// lines only in a are prefixed "- ", and lines only in b are prefixed "+ ".
+func lineDiff(a, b []string) []string {
+	lcs := longestCommonSubsequence(a, b)
+
+	var out []string
+	i, j, k := 0, 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && k < len(lcs) && a[i] == lcs[k] && b[j] == lcs[k]:
+			out = append(out, "  "+a[i])
+			i++
+			j++
+			k++
+		case i < len(a) && (k >= len(lcs) || a[i] != lcs[k]):
+			out = append(out, "- "+a[i])
+			i++
+		default:
+			out = append(out, "+ "+b[j])
+			j++
+		}
+	}
+	return out
+}
+
+// longestCommonSubsequence returns the longest common subsequence of a and b
+// via the textbook dynamic-programming algorithm.
+func longestCommonSubsequence(a, b []string) []string {
+	n, m := len(a), len(b)
+	dp := make([][]int, n+1)
+	for i := range dp {
+		dp[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				dp[i][j] = dp[i+1][j+1] + 1
+			case dp[i+1][j] >= dp[i][j+1]:
+				dp[i][j] = dp[i+1][j]
+			default:
+				dp[i][j] = dp[i][j+1]
+			}
+		}
+	}
+
+	var lcs []string
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			lcs = append(lcs, a[i])
+			i++
+			j++
+		case dp[i+1][j] >= dp[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return lcs
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package explain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLongestCommonSubsequence(t *testing.T) {
+	tests := []struct {
+		name string
+		a    []string
+		b    []string
+		want []string
+	}{
+		{
+			name: "both empty",
+			a:    nil,
+			b:    nil,
+			want: nil,
+		},
+		{
+			name: "no common lines",
+			a:    []string{"a", "b"},
+			b:    []string{"c", "d"},
+			want: nil,
+		},
+		{
+			name: "identical",
+			a:    []string{"a", "b", "c"},
+			b:    []string{"a", "b", "c"},
+			want: []string{"a", "b", "c"},
+		},
+		{
+			name: "interleaved common lines",
+			a:    []string{"a", "b", "c", "d"},
+			b:    []string{"x", "b", "y", "d"},
+			want: []string{"b", "d"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := longestCommonSubsequence(tt.a, tt.b)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("longestCommonSubsequence(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLineDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a    []string
+		b    []string
+		want []string
+	}{
+		{
+			name: "identical",
+			a:    []string{"spec.replicas\ttype=integer\trequired=false\t"},
+			b:    []string{"spec.replicas\ttype=integer\trequired=false\t"},
+			want: []string{"  spec.replicas\ttype=integer\trequired=false\t"},
+		},
+		{
+			name: "field added",
+			a:    []string{"spec"},
+			b:    []string{"spec", "spec.selector"},
+			want: []string{"  spec", "+ spec.selector"},
+		},
+		{
+			name: "field removed",
+			a:    []string{"spec", "spec.selector"},
+			b:    []string{"spec"},
+			want: []string{"  spec", "- spec.selector"},
+		},
+		{
+			name: "field changed is shown as a removal plus an addition",
+			a:    []string{"spec.replicas\ttype=integer\trequired=false\t"},
+			b:    []string{"spec.replicas\ttype=string\trequired=false\t"},
+			want: []string{
+				"- spec.replicas\ttype=integer\trequired=false\t",
+				"+ spec.replicas\ttype=string\trequired=false\t",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := lineDiff(tt.a, tt.b)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("lineDiff(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
@@ -17,7 +17,12 @@ limitations under the License.
 package explain
 
 import (
+	"bufio"
 	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -25,6 +30,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/discovery"
+	"k8s.io/kube-openapi/pkg/util/proto"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/explain"
 	explainv2 "k8s.io/kubectl/pkg/explain/v2"
@@ -42,15 +48,39 @@ var (
 
 			<type>.<fieldName>[.<fieldName>]
 
-		Add the --recursive flag to display all of the fields at once without descriptions.
-		Information about each field is retrieved from the server in OpenAPI format.`))
+		Add the --depth=N flag to display N levels of fields of fields (the default,
+		1, preserves the historical one-level-deep behavior); use --depth=-1 to
+		display all levels of fields at once (--recursive is still accepted as a
+		deprecated alias for --depth=-1).
+		Add --fields=<glob,...> to restrict output to matching subpaths, and
+		--required-only to print only fields the schema marks as required.
+		Information about each field is retrieved from the server in OpenAPI format.
+
+		Add the --interactive flag to resolve a partial or fuzzy field expression
+		(e.g. "no.*pro" or "providerID") instead of typing the full dotted path,
+		choosing from a numbered menu when more than one field matches.
+
+		Use --output to render the schema as json, yaml, markdown, or a single
+		value selected with jsonpath=<expr>, instead of the default plaintext
+		description.
+
+		Use --schema-file to explain resources against a local OpenAPI document
+		instead of a live cluster, and --schema-cache-dir to cache the document
+		downloaded from a cluster across invocations.
+
+		Pass more than one resource to explain each in turn, separated by a
+		divider, or add --diff to print a line diff between exactly two of
+		them instead.`))
 
 	explainExamples = templates.Examples(i18n.T(`
 		# Get the documentation of the resource and its fields
 		kubectl explain pods
 
 		# Get the documentation of a specific field of a resource
-		kubectl explain pods.spec.containers`))
+		kubectl explain pods.spec.containers
+
+		# Compare the spec of two resources
+		kubectl explain deployment.spec statefulset.spec --diff`))
 )
 
 type ExplainOptions struct {
@@ -58,7 +88,45 @@ type ExplainOptions struct {
 
 	CmdParent  string
 	APIVersion string
-	Recursive  bool
+
+	// Depth limits how many levels of nested fields are printed (1 preserves
+	// the historical one-level-deep behavior; -1 is unbounded, replacing the
+	// old boolean --recursive).
+	Depth int
+	// Fields, when non-empty, restricts output to subpaths matching one of
+	// these glob patterns (e.g. "spec.containers*").
+	Fields []string
+	// RequiredOnly prints only fields the schema marks as required.
+	RequiredOnly bool
+
+	// Interactive enables fuzzy/substring resolution of the field path
+	// instead of requiring the fully-qualified dotted path.
+	Interactive bool
+	// DisablePrintPath suppresses the KIND/VERSION/FIELD header that is
+	// otherwise printed ahead of the description when Interactive is set.
+	// Without it, the output for the chosen path is the same as that of a
+	// plain `kubectl explain <path>` call, which prints that header; with it,
+	// the header is left out.
+	DisablePrintPath bool
+
+	// SchemaFile, when set, loads the OpenAPI v2 (JSON or protobuf) document
+	// from this path instead of contacting a cluster, for air-gapped use and
+	// CI jobs validating field paths against a pinned schema.
+	SchemaFile string
+	// SchemaCacheDir, when set, caches the OpenAPI document downloaded from
+	// the cluster on disk, keyed by server URL and schema hash, so repeat
+	// invocations against the same cluster skip the network round trip.
+	SchemaCacheDir string
+
+	// Diff, when set, requires exactly two resource arguments and prints a
+	// line diff of their schema subtrees instead of explaining them in turn.
+	Diff bool
+
+	// recursive is the deprecated predecessor of Depth: --recursive=true is
+	// equivalent to --depth=-1. It is only consulted in Complete when the
+	// flag was actually set, so --depth keeps working as the only way to ask
+	// for one or a handful of levels.
+	recursive bool
 
 	args []string
 
@@ -81,6 +149,7 @@ func NewExplainOptions(parent string, streams genericclioptions.IOStreams) *Expl
 	return &ExplainOptions{
 		IOStreams:       streams,
 		CmdParent:       parent,
+		Depth:           1,
 		EnableOpenAPIV3: cmdutil.ExplainOpenapiV3.IsEnabled(),
 		OutputFormat:    "plaintext",
 	}
@@ -91,7 +160,7 @@ func NewCmdExplain(parent string, f cmdutil.Factory, streams genericclioptions.I
 	o := NewExplainOptions(parent, streams)
 
 	cmd := &cobra.Command{
-		Use:                   "explain RESOURCE",
+		Use:                   "explain RESOURCE [RESOURCE...]",
 		DisableFlagsInUseLine: true,
 		Short:                 i18n.T("Get documentation for a resource"),
 		Long:                  explainLong + "\n\n" + cmdutil.SuggestAPIResources(parent),
@@ -102,13 +171,18 @@ func NewCmdExplain(parent string, f cmdutil.Factory, streams genericclioptions.I
 			cmdutil.CheckErr(o.Run())
 		},
 	}
-	cmd.Flags().BoolVar(&o.Recursive, "recursive", o.Recursive, "Print the fields of fields (Currently only 1 level deep)")
+	cmd.Flags().IntVar(&o.Depth, "depth", o.Depth, "Print fields up to this many levels deep below the resolved path (-1 for unbounded, replacing the old --recursive)")
+	cmd.Flags().BoolVar(&o.recursive, "recursive", o.recursive, "Print the fields of fields at every level (deprecated alias for --depth=-1)")
+	cmd.Flags().MarkDeprecated("recursive", "use --depth=-1 instead")
+	cmd.Flags().StringSliceVar(&o.Fields, "fields", o.Fields, "Restrict output to subpaths matching one of these glob patterns (e.g. spec.containers*)")
+	cmd.Flags().BoolVar(&o.RequiredOnly, "required-only", o.RequiredOnly, "Print only fields marked required in the schema")
 	cmd.Flags().StringVar(&o.APIVersion, "api-version", o.APIVersion, "Get different explanations for particular API version (API group/version)")
-
-	// Only enable --output as a valid flag if the feature is enabled
-	if o.EnableOpenAPIV3 {
-		cmd.Flags().StringVar(&o.OutputFormat, "output", o.OutputFormat, "Format in which to render the schema")
-	}
+	cmd.Flags().BoolVar(&o.Interactive, "interactive", o.Interactive, "Resolve the field path with fuzzy/substring matching instead of requiring the exact dotted path, prompting for a choice when more than one field matches")
+	cmd.Flags().BoolVar(&o.DisablePrintPath, "disable-print-path", o.DisablePrintPath, "When used with --interactive, don't print the resolved field path ahead of its description")
+	cmd.Flags().StringVar(&o.OutputFormat, "output", o.OutputFormat, "Format in which to render the schema (plaintext, json, yaml, markdown, jsonpath=<expr>)")
+	cmd.Flags().StringVar(&o.SchemaFile, "schema-file", o.SchemaFile, "Explain resources against a local OpenAPI v2 (JSON/protobuf) document instead of contacting a cluster; OpenAPI v3 documents are not supported")
+	cmd.Flags().StringVar(&o.SchemaCacheDir, "schema-cache-dir", o.SchemaCacheDir, "Directory used to cache the OpenAPI document downloaded from the cluster, keyed by server URL and schema hash, to skip the network round trip on repeat invocations")
+	cmd.Flags().BoolVar(&o.Diff, "diff", o.Diff, "Show a line diff between the schemas of exactly two resources instead of printing them")
 
 	return cmd
 }
@@ -120,18 +194,39 @@ func (o *ExplainOptions) Complete(f cmdutil.Factory, cmd *cobra.Command, args []
 		return err
 	}
 
-	o.Schema, err = f.OpenAPISchema()
-	if err != nil {
-		return err
+	if cmd.Flags().Changed("recursive") && o.recursive {
+		o.Depth = -1
 	}
 
-	// Only openapi v3 needs the discovery client.
-	if o.EnableOpenAPIV3 {
-		discoveryClient, err := f.ToDiscoveryClient()
+	if o.SchemaFile != "" {
+		if o.EnableOpenAPIV3 {
+			return fmt.Errorf("--schema-file is not yet supported together with the OpenAPI v3 renderer; unset KUBECTL_EXPLAIN_OPENAPIV3 or omit --schema-file")
+		}
+		// Offline mode: the schema comes from disk, so there is no cluster to
+		// discover groups from or to cache against.
+		o.Schema, err = loadOpenAPISchemaFromFile(o.SchemaFile)
 		if err != nil {
 			return err
 		}
-		o.DiscoveryClient = discoveryClient
+		o.args = args
+		return nil
+	}
+
+	// The discovery client is used by openapi v3 rendering and, for both
+	// schema versions, to disambiguate resource tokens that collide with a
+	// discovered API group's suffix (see disambiguateByGroupSuffix).
+	o.DiscoveryClient, err = f.ToDiscoveryClient()
+	if err != nil {
+		return err
+	}
+
+	if o.SchemaCacheDir != "" {
+		o.Schema, err = loadOpenAPISchemaWithCache(o.SchemaCacheDir, o.DiscoveryClient)
+	} else {
+		o.Schema, err = f.OpenAPISchema()
+	}
+	if err != nil {
+		return err
 	}
 
 	o.args = args
@@ -142,67 +237,364 @@ func (o *ExplainOptions) Validate() error {
 	if len(o.args) == 0 {
 		return fmt.Errorf("You must specify the type of resource to explain. %s\n", cmdutil.SuggestAPIResources(o.CmdParent))
 	}
-	if len(o.args) > 1 {
-		return fmt.Errorf("We accept only this format: explain RESOURCE\n")
+	if o.Diff && len(o.args) != 2 {
+		return fmt.Errorf("--diff requires exactly two resources, e.g. explain deployment.spec statefulset.spec --diff\n")
+	}
+	if o.Interactive && len(o.args) != 1 {
+		return fmt.Errorf("--interactive only supports a single resource\n")
 	}
 
 	return nil
 }
 
-// Run executes the appropriate steps to print a model's documentation
+// Run executes the appropriate steps to print a model's documentation. With
+// a single argument it behaves as always; with several it explains each in
+// turn, separated by a divider, or -- with --diff -- prints a line diff of
+// the two resources' schemas instead.
 func (o *ExplainOptions) Run() error {
-	recursive := o.Recursive
-	apiVersionString := o.APIVersion
+	if o.Diff {
+		return o.runDiff(o.args[0], o.args[1])
+	}
 
-	var fullySpecifiedGVR schema.GroupVersionResource
-	var fieldsPath []string
-	var err error
-	if len(apiVersionString) == 0 {
-		fullySpecifiedGVR, fieldsPath, err = explain.SplitAndParseResourceRequestWithMatchingPrefix(o.args[0], o.Mapper)
-		if err != nil {
+	for i, arg := range o.args {
+		if i > 0 {
+			fmt.Fprintln(o.Out, strings.Repeat("-", 80))
+		}
+		if err := o.explainOne(arg); err != nil {
 			return err
 		}
-	} else {
-		// TODO: After we figured out the new syntax to separate group and resource, allow
-		// the users to use it in explain (kubectl explain <group><syntax><resource>).
-		// Refer to issue #16039 for why we do this. Refer to PR #15808 that used "/" syntax.
-		fullySpecifiedGVR, fieldsPath, err = explain.SplitAndParseResourceRequest(o.args[0], o.Mapper)
+	}
+	return nil
+}
+
+// resolve splits arg into a fully-specified GVR and field path, applying
+// --api-version and group-suffix disambiguation the same way regardless of
+// how many resources are being explained.
+func (o *ExplainOptions) resolve(arg string) (schema.GroupVersionResource, []string, error) {
+	if len(o.APIVersion) == 0 {
+		fullySpecifiedGVR, fieldsPath, err := explain.SplitAndParseResourceRequestWithMatchingPrefix(arg, o.Mapper)
 		if err != nil {
-			return err
+			return schema.GroupVersionResource{}, nil, err
 		}
+		if disambiguated, disambiguatedPath, ok := o.disambiguateByGroupSuffix(fullySpecifiedGVR, fieldsPath); ok {
+			fullySpecifiedGVR, fieldsPath = disambiguated, disambiguatedPath
+		}
+		return fullySpecifiedGVR, fieldsPath, nil
+	}
+
+	// TODO: After we figured out the new syntax to separate group and resource, allow
+	// the users to use it in explain (kubectl explain <group><syntax><resource>).
+	// Refer to issue #16039 for why we do this. Refer to PR #15808 that used "/" syntax.
+	return explain.SplitAndParseResourceRequest(arg, o.Mapper)
+}
+
+// lookupSchema resolves fullySpecifiedGVR to a Kind, applying --api-version,
+// and looks up its model in o.Schema.
+func (o *ExplainOptions) lookupSchema(fullySpecifiedGVR schema.GroupVersionResource) (schema.GroupVersionKind, proto.Schema, error) {
+	gvk, _ := o.Mapper.KindFor(fullySpecifiedGVR)
+	if gvk.Empty() {
+		var err error
+		gvk, err = o.Mapper.KindFor(fullySpecifiedGVR.GroupResource().WithVersion(""))
+		if err != nil {
+			return schema.GroupVersionKind{}, nil, err
+		}
+	}
+
+	if len(o.APIVersion) != 0 {
+		apiVersion, err := schema.ParseGroupVersion(o.APIVersion)
+		if err != nil {
+			return schema.GroupVersionKind{}, nil, err
+		}
+		gvk = apiVersion.WithKind(gvk.Kind)
+	}
+
+	modelSchema := o.Schema.LookupResource(gvk)
+	if modelSchema == nil {
+		return schema.GroupVersionKind{}, nil, fmt.Errorf("couldn't find resource for %q", gvk)
+	}
+	return gvk, modelSchema, nil
+}
+
+// explainOne resolves and prints the documentation for a single resource
+// argument, following whichever rendering mode (plaintext, interactive, or a
+// structured --output format) was requested.
+func (o *ExplainOptions) explainOne(arg string) error {
+	fullySpecifiedGVR, fieldsPath, err := o.resolve(arg)
+	if err != nil {
+		return err
 	}
 
+	traversal := o.traversal()
+
 	if o.EnableOpenAPIV3 {
+		// explainv2 has its own output-format handling rather than the
+		// json/yaml/markdown/jsonpath registry in pkg/explain, so passing
+		// o.OutputFormat straight through would give inconsistent formats
+		// depending on which OpenAPI version happened to back the request.
+		// Until the two are unified, reject structured formats explicitly
+		// instead of silently falling through to whatever (or nothing)
+		// explainv2 makes of them.
+		if explain.IsStructuredOutputFormat(o.OutputFormat) {
+			return fmt.Errorf("--output=%s is not yet supported with the OpenAPI v3 renderer; unset KUBECTL_EXPLAIN_OPENAPIV3 or use --output=plaintext", o.OutputFormat)
+		}
+		// Likewise, explainv2.PrintModelDescription doesn't accept a
+		// TraversalOptions, so --depth/--fields/--required-only can't be
+		// honored here; reject rather than silently ignore them.
+		if !traversal.IsDefault() {
+			return fmt.Errorf("--depth, --fields, and --required-only are not yet supported with the OpenAPI v3 renderer; unset KUBECTL_EXPLAIN_OPENAPIV3 to use them")
+		}
 		return explainv2.PrintModelDescription(
 			fieldsPath,
 			o.Out,
 			o.DiscoveryClient.OpenAPIV3(),
 			fullySpecifiedGVR,
-			recursive,
+			traversal.Recursive(),
 			o.OutputFormat,
 		)
 	}
 
-	gvk, _ := o.Mapper.KindFor(fullySpecifiedGVR)
-	if gvk.Empty() {
-		gvk, err = o.Mapper.KindFor(fullySpecifiedGVR.GroupResource().WithVersion(""))
-		if err != nil {
-			return err
+	gvk, modelSchema, err := o.lookupSchema(fullySpecifiedGVR)
+	if err != nil {
+		return err
+	}
+
+	if o.Interactive {
+		if len(fieldsPath) == 0 {
+			return fmt.Errorf("--interactive requires a field expression, e.g. kubectl explain %s.<query>", arg)
 		}
+		return o.runInteractive(gvk, modelSchema, strings.Join(fieldsPath, "."))
 	}
 
-	if len(apiVersionString) != 0 {
-		apiVersion, err := schema.ParseGroupVersion(apiVersionString)
+	if explain.IsStructuredOutputFormat(o.OutputFormat) {
+		return explain.Render(o.OutputFormat, fieldsPath, o.Out, modelSchema, traversal)
+	}
+
+	return explain.RenderPlaintext(fieldsPath, o.Out, modelSchema, gvk, traversal, true)
+}
+
+// traversal bundles --depth, --fields, and --required-only into the shared
+// config consumed by the v1 renderers. The v2 (OpenAPI v3) path accepts only
+// the default options and returns an error for any others.
+func (o *ExplainOptions) traversal() explain.TraversalOptions {
+	return explain.TraversalOptions{
+		Depth:        o.Depth,
+		Fields:       o.Fields,
+		RequiredOnly: o.RequiredOnly,
+	}
+}
+
+// disambiguateByGroupSuffix handles resource tokens like
+// "authentications.metal3.io" or "authentications.metal3.io.spec", where
+// SplitAndParseResourceRequestWithMatchingPrefix may have matched
+// "authentications" against the RESTMapper's highest-priority group rather
+// than the one the user actually typed. It looks for the longest prefix of
+// fieldsPath that, joined with dots, names a group discovered on the server
+// and, if one is found and differs from the group already resolved, re-splits
+// the token so the resource keeps its original name against that group and
+// whatever remains of fieldsPath becomes the field path. It reports
+// ok=false when there is nothing to disambiguate, in which case the caller
+// should keep gvr/fieldsPath as-is.
+func (o *ExplainOptions) disambiguateByGroupSuffix(gvr schema.GroupVersionResource, fieldsPath []string) (schema.GroupVersionResource, []string, bool) {
+	if o.DiscoveryClient == nil {
+		return gvr, fieldsPath, false
+	}
+
+	groups, err := o.DiscoveryClient.ServerGroups()
+	if err != nil {
+		return gvr, fieldsPath, false
+	}
+
+	var bestGroup string
+	var bestSegments int
+	for _, g := range groups.Groups {
+		if g.Name == "" || g.Name == gvr.Group {
+			continue
+		}
+		segments := strings.Split(g.Name, ".")
+		if len(segments) > len(fieldsPath) {
+			continue
+		}
+		if strings.Join(fieldsPath[:len(segments)], ".") != g.Name {
+			continue
+		}
+		if len(g.Name) > len(bestGroup) {
+			bestGroup = g.Name
+			bestSegments = len(segments)
+		}
+	}
+	if bestGroup == "" {
+		return gvr, fieldsPath, false
+	}
+
+	disambiguatedGVR, err := o.Mapper.ResourceFor(schema.GroupVersionResource{Group: bestGroup, Resource: gvr.Resource})
+	if err != nil {
+		return gvr, fieldsPath, false
+	}
+
+	var disambiguatedPath []string
+	if remainder := fieldsPath[bestSegments:]; len(remainder) > 0 {
+		disambiguatedPath = remainder
+	}
+	return disambiguatedGVR, disambiguatedPath, true
+}
+
+// runInteractive resolves query against every field path discoverable in
+// modelSchema using glob-style wildcards and case-insensitive substring
+// matching against both the leaf field name and the full dotted path. A
+// single match is explained directly; multiple matches are presented as a
+// numbered menu on o.Out and the choice is read from o.In.
+func (o *ExplainOptions) runInteractive(gvk schema.GroupVersionKind, modelSchema proto.Schema, query string) error {
+	collector := &fieldPathCollector{}
+	modelSchema.Accept(collector)
+
+	matches := matchFieldPaths(collector.paths, query)
+	if len(matches) == 0 {
+		return fmt.Errorf("no fields matching %q were found for %q", query, gvk)
+	}
+
+	selected := matches[0]
+	if len(matches) > 1 {
+		var err error
+		selected, err = o.pickFieldPath(matches)
 		if err != nil {
 			return err
 		}
-		gvk = apiVersion.WithKind(gvk.Kind)
 	}
 
-	schema := o.Schema.LookupResource(gvk)
-	if schema == nil {
-		return fmt.Errorf("couldn't find resource for %q", gvk)
+	return explain.RenderPlaintext(strings.Split(selected, "."), o.Out, modelSchema, gvk, o.traversal(), !o.DisablePrintPath)
+}
+
+// pickFieldPath prints a plain numbered menu of candidate field paths and
+// reads the user's choice from o.In. It is the non-TTY-friendly fallback;
+// an fzf-style picker can be layered on top once a fuzzy-finder dependency
+// is vendored.
+func (o *ExplainOptions) pickFieldPath(matches []string) (string, error) {
+	sort.Strings(matches)
+
+	fmt.Fprintln(o.Out, "Multiple fields matched, please choose one:")
+	for i, m := range matches {
+		fmt.Fprintf(o.Out, "  [%d] %s\n", i+1, m)
+	}
+	fmt.Fprint(o.Out, "Selection: ")
+
+	line, err := bufio.NewReader(o.In).ReadString('\n')
+	if err != nil {
+		return "", err
 	}
+	line = strings.TrimSpace(line)
 
-	return explain.PrintModelDescription(fieldsPath, o.Out, schema, gvk, recursive)
+	idx, err := strconv.Atoi(line)
+	if err != nil || idx < 1 || idx > len(matches) {
+		return "", fmt.Errorf("invalid selection %q", line)
+	}
+	return matches[idx-1], nil
+}
+
+// maxFieldPathDepth bounds how many nested fields fieldPathCollector will
+// descend into. It exists alongside the visited-reference tracking below as
+// a hard backstop: a self-referential schema (e.g. a CRD's embedded
+// JSONSchemaProps) could otherwise recurse through distinct field names
+// forever even though no single $ref is ever revisited.
+const maxFieldPathDepth = 64
+
+// fieldPathCollector is a proto.SchemaVisitor that records the fully
+// qualified, dot-separated path of every field reachable from the schema
+// node it is run against. visited tracks the $ref names already expanded
+// along the current path so a self-referential schema terminates instead of
+// recursing forever; it is copied (not mutated in place) before a reference
+// is expanded, so the same reference can still be expanded again on a
+// sibling branch.
+type fieldPathCollector struct {
+	prefix  []string
+	paths   []string
+	visited map[string]bool
+}
+
+func (c *fieldPathCollector) record() {
+	if len(c.prefix) == 0 {
+		return
+	}
+	// An array or map records its path and then visits its element schema
+	// at the same prefix; skip the element's record so each path appears once.
+	path := strings.Join(c.prefix, ".")
+	if n := len(c.paths); n > 0 && c.paths[n-1] == path {
+		return
+	}
+	c.paths = append(c.paths, path)
+}
+
+func (c *fieldPathCollector) VisitPrimitive(p *proto.Primitive) {
+	c.record()
+}
+
+func (c *fieldPathCollector) VisitArbitrary(a *proto.Arbitrary) {
+	c.record()
+}
+
+func (c *fieldPathCollector) VisitReference(r proto.Reference) {
+	if ref := r.Reference(); ref != "" {
+		if c.visited[ref] {
+			return
+		}
+		visited := make(map[string]bool, len(c.visited)+1)
+		for k := range c.visited {
+			visited[k] = true
+		}
+		visited[ref] = true
+		c.visited = visited
+	}
+	r.SubSchema().Accept(c)
+}
+
+func (c *fieldPathCollector) VisitArray(a *proto.Array) {
+	c.record()
+	a.SubType.Accept(c)
+}
+
+func (c *fieldPathCollector) VisitMap(m *proto.Map) {
+	c.record()
+	m.SubType.Accept(c)
+}
+
+func (c *fieldPathCollector) VisitKind(k *proto.Kind) {
+	c.record()
+	if len(c.prefix) >= maxFieldPathDepth {
+		return
+	}
+	for _, name := range k.FieldOrder {
+		sub := &fieldPathCollector{
+			prefix:  append(append([]string{}, c.prefix...), name),
+			visited: c.visited,
+		}
+		k.Fields[name].Accept(sub)
+		c.paths = append(c.paths, sub.paths...)
+	}
+}
+
+// matchFieldPaths returns every entry in paths whose leaf name or full
+// dotted path matches query, either as a glob pattern (e.g. "no.*pro") or
+// as a case-insensitive substring (e.g. "providerID").
+func matchFieldPaths(paths []string, query string) []string {
+	lowerQuery := strings.ToLower(query)
+
+	var matches []string
+	for _, p := range paths {
+		leaf := p
+		if i := strings.LastIndex(p, "."); i >= 0 {
+			leaf = p[i+1:]
+		}
+
+		if ok, _ := filepath.Match(query, p); ok {
+			matches = append(matches, p)
+			continue
+		}
+		if ok, _ := filepath.Match(query, leaf); ok {
+			matches = append(matches, p)
+			continue
+		}
+		if strings.Contains(strings.ToLower(p), lowerQuery) || strings.Contains(strings.ToLower(leaf), lowerQuery) {
+			matches = append(matches, p)
+		}
+	}
+	return matches
 }
@@ -0,0 +1,256 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package explain
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/discovery"
+	"k8s.io/kube-openapi/pkg/util/proto"
+)
+
+func TestMatchFieldPaths(t *testing.T) {
+	paths := []string{
+		"spec",
+		"spec.containers",
+		"spec.containers.image",
+		"spec.initContainers",
+		"spec.providerID",
+		"status.phase",
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{
+			name:  "glob matches full path",
+			query: "spec.*Containers",
+			want:  []string{"spec.initContainers"},
+		},
+		{
+			name:  "glob matches leaf name",
+			query: "image",
+			want:  []string{"spec.containers.image"},
+		},
+		{
+			name:  "case-insensitive substring against full path",
+			query: "PROVIDERID",
+			want:  []string{"spec.providerID"},
+		},
+		{
+			name:  "case-insensitive substring against leaf",
+			query: "phase",
+			want:  []string{"status.phase"},
+		},
+		{
+			name:  "substring matching multiple entries",
+			query: "containers",
+			want:  []string{"spec.containers", "spec.containers.image", "spec.initContainers"},
+		},
+		{
+			name:  "no match",
+			query: "nonexistent",
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := matchFieldPaths(paths, tt.query)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("matchFieldPaths(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRunInteractiveOutput guards against the resolved field path header
+// being printed twice: once by runInteractive itself and once more by
+// explain.RenderPlaintext, which prints its own KIND/VERSION/FIELD block.
+func TestRunInteractiveOutput(t *testing.T) {
+	modelSchema := &proto.Kind{
+		BaseSchema: proto.BaseSchema{Description: "pod spec"},
+		FieldOrder: []string{"restartPolicy"},
+		Fields: map[string]proto.Schema{
+			"restartPolicy": &proto.Primitive{BaseSchema: proto.BaseSchema{Description: "restart policy"}, Type: "string"},
+		},
+	}
+	gvk := schema.GroupVersionKind{Kind: "Pod", Version: "v1"}
+
+	tests := []struct {
+		name             string
+		disablePrintPath bool
+		wantHeaderCount  int
+	}{
+		{name: "header is printed exactly once by default", disablePrintPath: false, wantHeaderCount: 1},
+		{name: "header is suppressed with --disable-print-path", disablePrintPath: true, wantHeaderCount: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			o := &ExplainOptions{
+				IOStreams:        genericclioptions.IOStreams{Out: &out, ErrOut: &out},
+				Depth:            1,
+				DisablePrintPath: tt.disablePrintPath,
+			}
+
+			if err := o.runInteractive(gvk, modelSchema, "restartPolicy"); err != nil {
+				t.Fatalf("runInteractive() returned error: %v", err)
+			}
+
+			got := out.String()
+			if count := strings.Count(got, "KIND:"); count != tt.wantHeaderCount {
+				t.Errorf("output contains %d \"KIND:\" header(s), want %d:\n%s", count, tt.wantHeaderCount, got)
+			}
+			if !strings.Contains(got, "restart policy") {
+				t.Errorf("output = %q, want it to still contain the field description", got)
+			}
+		})
+	}
+}
+
+// fakeRESTMapper implements just enough of meta.RESTMapper for
+// disambiguateByGroupSuffix; every other method panics if called.
+type fakeRESTMapper struct {
+	meta.RESTMapper
+	resourceFor func(input schema.GroupVersionResource) (schema.GroupVersionResource, error)
+}
+
+func (f *fakeRESTMapper) ResourceFor(input schema.GroupVersionResource) (schema.GroupVersionResource, error) {
+	return f.resourceFor(input)
+}
+
+// fakeDiscoveryClient implements just enough of discovery.DiscoveryInterface
+// for disambiguateByGroupSuffix; every other method panics if called.
+type fakeDiscoveryClient struct {
+	discovery.DiscoveryInterface
+	groups *metav1.APIGroupList
+	err    error
+}
+
+func (f *fakeDiscoveryClient) ServerGroups() (*metav1.APIGroupList, error) {
+	return f.groups, f.err
+}
+
+func TestDisambiguateByGroupSuffix(t *testing.T) {
+	discoveredGroups := &metav1.APIGroupList{
+		Groups: []metav1.APIGroup{
+			{Name: "metal3.io"},
+			{Name: "io"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		gvr         schema.GroupVersionResource
+		fieldsPath  []string
+		discovery   discovery.DiscoveryInterface
+		resourceFor func(input schema.GroupVersionResource) (schema.GroupVersionResource, error)
+		wantGVR     schema.GroupVersionResource
+		wantPath    []string
+		wantOK      bool
+	}{
+		{
+			name:       "no discovery client",
+			gvr:        schema.GroupVersionResource{Resource: "authentications"},
+			fieldsPath: []string{"metal3.io"},
+			discovery:  nil,
+			wantOK:     false,
+		},
+		{
+			name:       "no group suffix matches",
+			gvr:        schema.GroupVersionResource{Group: "authentication.k8s.io", Resource: "authentications"},
+			fieldsPath: []string{"spec"},
+			discovery:  &fakeDiscoveryClient{groups: discoveredGroups},
+			wantOK:     false,
+		},
+		{
+			name:       "longest matching suffix re-splits resource from its group",
+			gvr:        schema.GroupVersionResource{Group: "authentication.k8s.io", Resource: "authentications"},
+			fieldsPath: []string{"metal3", "io"},
+			discovery:  &fakeDiscoveryClient{groups: discoveredGroups},
+			resourceFor: func(input schema.GroupVersionResource) (schema.GroupVersionResource, error) {
+				if input.Group != "metal3.io" || input.Resource != "authentications" {
+					return schema.GroupVersionResource{}, fmt.Errorf("unexpected lookup %+v", input)
+				}
+				return schema.GroupVersionResource{Group: "metal3.io", Version: "v1alpha1", Resource: "authentications"}, nil
+			},
+			wantGVR:  schema.GroupVersionResource{Group: "metal3.io", Version: "v1alpha1", Resource: "authentications"},
+			wantPath: nil,
+			wantOK:   true,
+		},
+		{
+			name:       "trailing field path after the group is preserved",
+			gvr:        schema.GroupVersionResource{Group: "authentication.k8s.io", Resource: "authentications"},
+			fieldsPath: []string{"metal3", "io", "spec"},
+			discovery:  &fakeDiscoveryClient{groups: discoveredGroups},
+			resourceFor: func(input schema.GroupVersionResource) (schema.GroupVersionResource, error) {
+				if input.Group != "metal3.io" || input.Resource != "authentications" {
+					return schema.GroupVersionResource{}, fmt.Errorf("unexpected lookup %+v", input)
+				}
+				return schema.GroupVersionResource{Group: "metal3.io", Version: "v1alpha1", Resource: "authentications"}, nil
+			},
+			wantGVR:  schema.GroupVersionResource{Group: "metal3.io", Version: "v1alpha1", Resource: "authentications"},
+			wantPath: []string{"spec"},
+			wantOK:   true,
+		},
+		{
+			name:       "ResourceFor failure falls back to no disambiguation",
+			gvr:        schema.GroupVersionResource{Group: "authentication.k8s.io", Resource: "authentications"},
+			fieldsPath: []string{"metal3", "io"},
+			discovery:  &fakeDiscoveryClient{groups: discoveredGroups},
+			resourceFor: func(input schema.GroupVersionResource) (schema.GroupVersionResource, error) {
+				return schema.GroupVersionResource{}, fmt.Errorf("not found")
+			},
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &ExplainOptions{DiscoveryClient: tt.discovery}
+			if tt.resourceFor != nil {
+				o.Mapper = &fakeRESTMapper{resourceFor: tt.resourceFor}
+			}
+
+			gotGVR, gotPath, gotOK := o.disambiguateByGroupSuffix(tt.gvr, tt.fieldsPath)
+			if gotOK != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", gotOK, tt.wantOK)
+			}
+			if !tt.wantOK {
+				return
+			}
+			if gotGVR != tt.wantGVR {
+				t.Errorf("gvr = %+v, want %+v", gotGVR, tt.wantGVR)
+			}
+			if !reflect.DeepEqual(gotPath, tt.wantPath) {
+				t.Errorf("fieldsPath = %v, want %v", gotPath, tt.wantPath)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package explain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	openapi_v2 "github.com/google/gnostic-models/openapiv2"
+	"google.golang.org/protobuf/proto"
+
+	"k8s.io/client-go/discovery"
+	"k8s.io/kubectl/pkg/util/openapi"
+)
+
+// loadOpenAPISchemaFromFile parses a locally stored OpenAPI v2 document
+// (JSON or protobuf, whichever the file contains) and wraps it the same way
+// the cluster-backed schema is wrapped, so the rest of ExplainOptions can't
+// tell the difference between --schema-file and a live cluster.
+func loadOpenAPISchemaFromFile(path string) (openapi.Resources, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading --schema-file %s: %w", path, err)
+	}
+
+	doc, err := parseOpenAPIV2Document(raw)
+	if err != nil {
+		return nil, fmt.Errorf("parsing --schema-file %s: %w", path, err)
+	}
+
+	return openapi.NewOpenAPIData(doc)
+}
+
+// parseOpenAPIV2Document accepts either the JSON or the protobuf encoding of
+// an OpenAPI v2 document, matching what a cluster's /openapi/v2 endpoint can
+// serve depending on the Accept header it's sent.
+func parseOpenAPIV2Document(raw []byte) (*openapi_v2.Document, error) {
+	if json.Valid(raw) {
+		return openapi_v2.ParseDocument(raw)
+	}
+	doc := &openapi_v2.Document{}
+	if err := proto.Unmarshal(raw, doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// loadOpenAPISchemaWithCache serves the OpenAPI document for discoveryClient
+// out of cacheDir when a cached copy already exists for the cluster's
+// current schema hash, and otherwise downloads it and writes it to cacheDir
+// for next time. The cache entry is keyed by both the cluster's server URL
+// and its schema hash, which is derived from the server version (see
+// schemaCachePath), so a cluster upgrade misses the old entry. A change that
+// leaves the server version as it is, such as a newly installed CRD, does not
+// change the key, and the old entry keeps being served. A cache entry that
+// fails to parse is treated as a miss and transparently refreshed.
+func loadOpenAPISchemaWithCache(cacheDir string, discoveryClient discovery.DiscoveryInterface) (openapi.Resources, error) {
+	cachePath, err := schemaCachePath(cacheDir, discoveryClient)
+	if err == nil {
+		if raw, err := os.ReadFile(cachePath); err == nil {
+			if doc, err := parseOpenAPIV2Document(raw); err == nil {
+				return openapi.NewOpenAPIData(doc)
+			}
+		}
+	}
+
+	doc, err := discoveryClient.OpenAPISchema()
+	if err != nil {
+		return nil, err
+	}
+
+	if cachePath != "" {
+		if raw, err := proto.Marshal(doc); err == nil {
+			if err := os.MkdirAll(cacheDir, 0o755); err == nil {
+				_ = os.WriteFile(cachePath, raw, 0o644)
+			}
+		}
+	}
+
+	return openapi.NewOpenAPIData(doc)
+}
+
+// schemaCachePath derives the cache file for discoveryClient's server,
+// hashing the server URL together with the cluster's reported schema hash
+// (its server version, the same cheap signal the rest of discovery uses to
+// decide a schema may have changed) so cache filenames are deterministic,
+// filesystem-safe, and automatically rotate when the cluster's OpenAPI
+// document is likely to have changed. ServerVersion is a single lightweight
+// call, far cheaper than re-downloading and re-parsing the full OpenAPI
+// document it guards against serving stale.
+func schemaCachePath(cacheDir string, discoveryClient discovery.DiscoveryInterface) (string, error) {
+	var serverURL string
+	if rc := discoveryClient.RESTClient(); rc != nil {
+		if req := rc.Get(); req != nil {
+			serverURL = req.URL().String()
+		}
+	}
+
+	version, err := discoveryClient.ServerVersion()
+	if err != nil {
+		return "", fmt.Errorf("checking cluster schema hash: %w", err)
+	}
+
+	sum := sha256.Sum256([]byte(serverURL + "|" + version.String()))
+	return filepath.Join(cacheDir, hex.EncodeToString(sum[:])+".openapi.pb"), nil
+}
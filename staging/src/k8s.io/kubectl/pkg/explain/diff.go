@@ -0,0 +1,130 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package explain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/kube-openapi/pkg/util/proto"
+)
+
+// FlattenedField is one entry of the comparison used by `explain --diff`: a
+// field's fully qualified path alongside the handful of properties that
+// matter when comparing two versions of the "same" Kind.
+type FlattenedField struct {
+	Path        string
+	Type        string
+	Required    bool
+	Description string
+}
+
+// String renders a FlattenedField the same way regardless of which side of a
+// diff it came from, so a line-level diff of two Flatten outputs only shows
+// fields that actually changed.
+func (f FlattenedField) String() string {
+	return fmt.Sprintf("%s\ttype=%s\trequired=%t\t%s", f.Path, f.Type, f.Required, f.Description)
+}
+
+// Flatten walks the schema node found at fieldsPath within s and returns one
+// FlattenedField for that node and one for each field nested in it, sorted by
+// path. An array or map is a single entry; the fields of its element schema
+// are not expanded. It is the basis for the line diff --diff performs
+// between two resources.
+func Flatten(fieldsPath []string, s proto.Schema) ([]FlattenedField, error) {
+	target, err := navigateToPath(fieldsPath, s)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []FlattenedField
+	flattenSchema(strings.Join(fieldsPath, "."), target, false, map[string]bool{}, 0, &fields)
+
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Path < fields[j].Path })
+	return fields, nil
+}
+
+// maxFlattenDepth bounds how many nested fields flattenSchema will descend
+// into, as a hard backstop alongside visited below: a self-referential
+// schema (e.g. a CRD's embedded JSONSchemaProps) could otherwise recurse
+// through distinct field names forever even though no single $ref is ever
+// revisited.
+const maxFlattenDepth = 64
+
+// flattenSchema appends one FlattenedField per field reachable from s to
+// out. visited tracks the $ref names already expanded along the current
+// path, so a cyclic schema terminates instead of recursing forever; it is
+// copied (not mutated in place) before a reference is followed, so the same
+// reference can still be expanded again on a sibling branch.
+func flattenSchema(path string, s proto.Schema, required bool, visited map[string]bool, depth int, out *[]FlattenedField) {
+	if depth > maxFlattenDepth {
+		return
+	}
+
+	for {
+		ref, ok := s.(proto.Reference)
+		if !ok {
+			break
+		}
+		refName := ref.Reference()
+		if refName != "" {
+			if visited[refName] {
+				return
+			}
+			next := make(map[string]bool, len(visited)+1)
+			for k := range visited {
+				next[k] = true
+			}
+			next[refName] = true
+			visited = next
+		}
+		s = ref.SubSchema()
+	}
+
+	if kind, ok := s.(*proto.Kind); ok {
+		*out = append(*out, FlattenedField{Path: path, Type: "object", Required: required, Description: kind.GetDescription()})
+
+		requiredFields := map[string]bool{}
+		for _, name := range kind.RequiredFields {
+			requiredFields[name] = true
+		}
+		for _, name := range kind.FieldOrder {
+			childPath := name
+			if path != "" {
+				childPath = path + "." + name
+			}
+			flattenSchema(childPath, kind.Fields[name], requiredFields[name], visited, depth+1, out)
+		}
+		return
+	}
+
+	fieldType := "object"
+	description := ""
+	switch t := s.(type) {
+	case *proto.Array:
+		fieldType = "array"
+		description = t.GetDescription()
+	case *proto.Map:
+		description = t.GetDescription()
+	case *proto.Primitive:
+		fieldType = t.Type
+		description = t.GetDescription()
+	}
+
+	*out = append(*out, FlattenedField{Path: path, Type: fieldType, Required: required, Description: description})
+}
@@ -0,0 +1,436 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package explain
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/jsonpath"
+	"k8s.io/kube-openapi/pkg/util/proto"
+)
+
+// Render writes the schema node found at fieldsPath within s to out, in the
+// format named by outputFormat. outputFormat is one of "json", "yaml",
+// "markdown", or "jsonpath=<expr>"; any other value is an error. t bounds
+// how deep the rendered subtree goes and which fields it includes, the same
+// as it would for the plaintext renderer. It is used by the v1 explain code
+// path; the v2 (OpenAPI v3) path does not call it and rejects these formats.
+func Render(outputFormat string, fieldsPath []string, out io.Writer, s proto.Schema, t TraversalOptions) error {
+	name, jsonPathExpr := splitOutputFormat(outputFormat)
+
+	target, err := navigateToPath(fieldsPath, s)
+	if err != nil {
+		return err
+	}
+	root := schemaToMap(target, strings.Join(fieldsPath, "."), t.Depth, t)
+
+	switch name {
+	case "json":
+		return renderJSON(out, root)
+	case "yaml":
+		return renderYAML(out, root)
+	case "markdown":
+		return renderMarkdown(out, fieldsPath, root)
+	case "jsonpath":
+		return renderJSONPath(out, root, jsonPathExpr)
+	default:
+		return fmt.Errorf("unsupported --output format %q: must be one of json, yaml, markdown, jsonpath=<expr>", outputFormat)
+	}
+}
+
+// RenderPlaintext prints the field(s) found at fieldsPath within s to out in
+// the traditional KIND/FIELD/DESCRIPTION/FIELDS layout, applying t's Depth,
+// Fields, and RequiredOnly exactly the way Render does for the structured
+// formats. Unlike printing driven by t.Recursive() alone, every level of
+// --depth and every --fields pattern actually changes what gets printed
+// here, rather than collapsing to a single "one level" or "all levels"
+// choice. printHeader controls whether the leading KIND/VERSION/FIELD block
+// is printed; passing false prints only the DESCRIPTION and FIELDS sections.
+func RenderPlaintext(fieldsPath []string, out io.Writer, s proto.Schema, gvk schema.GroupVersionKind, t TraversalOptions, printHeader bool) error {
+	target, err := navigateToPath(fieldsPath, s)
+	if err != nil {
+		return err
+	}
+	path := strings.Join(fieldsPath, ".")
+	root := schemaToMap(target, path, t.Depth, t)
+
+	if printHeader {
+		fmt.Fprintf(out, "KIND:     %s\n", gvk.Kind)
+		fmt.Fprintf(out, "VERSION:  %s\n\n", gvk.GroupVersion())
+
+		if len(fieldsPath) > 0 {
+			fmt.Fprintf(out, "FIELD: %s <%s>\n\n", fieldsPath[len(fieldsPath)-1], plaintextType(root))
+		}
+	}
+
+	if description, _ := root["description"].(string); description != "" {
+		fmt.Fprintf(out, "DESCRIPTION:\n%s\n\n", description)
+	}
+
+	writePlaintextFields(out, root, 0)
+	return nil
+}
+
+// plaintextType renders node's "type" the way the legacy plaintext printer
+// labels fields (e.g. "Object", "[]string", "map[string]string").
+func plaintextType(node map[string]interface{}) string {
+	typ, _ := node["type"].(string)
+	switch typ {
+	case "array":
+		if items, ok := node["items"].(map[string]interface{}); ok {
+			return "[]" + plaintextType(items)
+		}
+		return "[]Object"
+	case "object":
+		if _, ok := node["properties"]; ok {
+			return "Object"
+		}
+		if additionalProperties, ok := node["additionalProperties"].(map[string]interface{}); ok {
+			return "map[string]" + plaintextType(additionalProperties)
+		}
+		return "Object"
+	case "":
+		return "Object"
+	default:
+		return typ
+	}
+}
+
+// writePlaintextFields prints node's already depth/field-filtered
+// "properties" under a single top-level "FIELDS:" heading, indenting nested
+// levels so --depth values greater than 1 are visibly distinguishable from
+// the historical one-level-deep output.
+func writePlaintextFields(out io.Writer, node map[string]interface{}, indent int) {
+	properties, _ := node["properties"].(map[string]interface{})
+	if len(properties) == 0 {
+		return
+	}
+
+	requiredFields := map[string]bool{}
+	if names, ok := node["required"].([]string); ok {
+		for _, name := range names {
+			requiredFields[name] = true
+		}
+	}
+
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	prefix := strings.Repeat("  ", indent)
+	if indent == 0 {
+		fmt.Fprintln(out, "FIELDS:")
+	}
+	for _, name := range names {
+		child, _ := properties[name].(map[string]interface{})
+		marker := ""
+		if requiredFields[name] {
+			marker = " -required-"
+		}
+		fmt.Fprintf(out, "%s   %s\t<%s>%s\n", prefix, name, plaintextType(child), marker)
+		if description, _ := child["description"].(string); description != "" {
+			fmt.Fprintf(out, "%s     %s\n", prefix, description)
+		}
+		fmt.Fprintln(out)
+		writePlaintextFields(out, child, indent+1)
+	}
+}
+
+// IsStructuredOutputFormat reports whether outputFormat names one of the
+// renderers handled by Render, as opposed to the default plaintext
+// description printed by RenderPlaintext.
+func IsStructuredOutputFormat(outputFormat string) bool {
+	switch name, _ := splitOutputFormat(outputFormat); name {
+	case "json", "yaml", "markdown", "jsonpath":
+		return true
+	default:
+		return false
+	}
+}
+
+func splitOutputFormat(outputFormat string) (name, jsonPathExpr string) {
+	if rest, ok := strings.CutPrefix(outputFormat, "jsonpath="); ok {
+		return "jsonpath", rest
+	}
+	return outputFormat, ""
+}
+
+// navigateToPath walks s following fieldsPath and returns the schema node it
+// lands on, resolving $ref indirection as it goes.
+func navigateToPath(fieldsPath []string, s proto.Schema) (proto.Schema, error) {
+	current := resolveReference(s)
+	for _, name := range fieldsPath {
+		var next proto.Schema
+		switch t := current.(type) {
+		case *proto.Kind:
+			field, ok := t.Fields[name]
+			if !ok {
+				return nil, fmt.Errorf("field %q does not exist", name)
+			}
+			next = field
+		case *proto.Array:
+			next = t.SubType
+		case *proto.Map:
+			next = t.SubType
+		default:
+			return nil, fmt.Errorf("field %q does not exist", name)
+		}
+		current = resolveReference(next)
+	}
+	return current, nil
+}
+
+func resolveReference(s proto.Schema) proto.Schema {
+	if ref, ok := s.(proto.Reference); ok {
+		return resolveReference(ref.SubSchema())
+	}
+	return s
+}
+
+// maxSchemaMapDepth bounds how many nested fields schemaToMap will descend
+// into, as a hard backstop alongside visited below: a self-referential
+// schema (e.g. a CRD's embedded JSONSchemaProps) could otherwise recurse
+// through distinct field names forever even when --depth/--recursive
+// requests an unbounded traversal (depth < 0 never reaches zero on its own).
+const maxSchemaMapDepth = 64
+
+// schemaToMap converts a proto.Schema node into a plain map mirroring the
+// shape of a raw OpenAPI schema document ("type", "description",
+// "properties", "items", "required", ...), so it can be marshaled with the
+// standard library and queried with a JSONPath expression the same way a
+// user would query any other OpenAPI document. path is the node's fully
+// qualified field path (used to evaluate traversal.Fields), depth is the
+// number of further levels of "properties"/"items" still allowed (negative
+// means unbounded), and traversal additionally applies RequiredOnly
+// filtering at every level.
+func schemaToMap(s proto.Schema, path string, depth int, traversal TraversalOptions) map[string]interface{} {
+	return schemaToMapVisited(s, path, depth, traversal, map[string]bool{}, 0)
+}
+
+// schemaToMapVisited does the actual work for schemaToMap. visited tracks
+// the $ref names already expanded along the current path, so a cyclic
+// schema terminates instead of recursing forever; it is copied (not mutated
+// in place) before a reference is followed, so the same reference can still
+// be expanded again on a sibling branch. level is an absolute recursion
+// count, capped by maxSchemaMapDepth, that backstops schemas which recurse
+// through distinct field names without ever revisiting the same $ref.
+func schemaToMapVisited(s proto.Schema, path string, depth int, traversal TraversalOptions, visited map[string]bool, level int) map[string]interface{} {
+	for {
+		ref, ok := s.(proto.Reference)
+		if !ok {
+			break
+		}
+		refName := ref.Reference()
+		if refName != "" {
+			if visited[refName] {
+				return map[string]interface{}{"type": "object"}
+			}
+			next := make(map[string]bool, len(visited)+1)
+			for k := range visited {
+				next[k] = true
+			}
+			next[refName] = true
+			visited = next
+		}
+		s = ref.SubSchema()
+	}
+
+	m := map[string]interface{}{}
+	if level > maxSchemaMapDepth {
+		m["type"] = "object"
+		return m
+	}
+
+	switch t := s.(type) {
+	case *proto.Kind:
+		m["type"] = "object"
+		if t.GetDescription() != "" {
+			m["description"] = t.GetDescription()
+		}
+		if depth != 0 {
+			required := map[string]bool{}
+			for _, name := range t.RequiredFields {
+				required[name] = true
+			}
+
+			properties := map[string]interface{}{}
+			var requiredNames []string
+			for _, name := range t.FieldOrder {
+				if traversal.RequiredOnly && !required[name] {
+					continue
+				}
+				childPath := name
+				if path != "" {
+					childPath = path + "." + name
+				}
+				child := schemaToMapVisited(t.Fields[name], childPath, nextDepth(depth), traversal, visited, level+1)
+				if !traversal.matchesSubtree(childPath, child) {
+					continue
+				}
+				properties[name] = child
+				if required[name] {
+					requiredNames = append(requiredNames, name)
+				}
+			}
+			m["properties"] = properties
+			if len(requiredNames) > 0 {
+				sort.Strings(requiredNames)
+				m["required"] = requiredNames
+			}
+		}
+	case *proto.Array:
+		m["type"] = "array"
+		if t.GetDescription() != "" {
+			m["description"] = t.GetDescription()
+		}
+		if depth != 0 {
+			m["items"] = schemaToMapVisited(t.SubType, path, nextDepth(depth), traversal, visited, level+1)
+		}
+	case *proto.Map:
+		m["type"] = "object"
+		if t.GetDescription() != "" {
+			m["description"] = t.GetDescription()
+		}
+		if depth != 0 {
+			m["additionalProperties"] = schemaToMapVisited(t.SubType, path, nextDepth(depth), traversal, visited, level+1)
+		}
+	case *proto.Primitive:
+		m["type"] = t.Type
+		if t.GetDescription() != "" {
+			m["description"] = t.GetDescription()
+		}
+	default:
+		m["type"] = "object"
+	}
+
+	return m
+}
+
+// matchesSubtree reports whether node, rooted at path, should be kept given
+// traversal.Fields: either path itself matches one of the patterns, or some
+// descendant already baked into node does.
+func (traversal TraversalOptions) matchesSubtree(path string, node map[string]interface{}) bool {
+	if traversal.MatchesField(path) {
+		return true
+	}
+	if properties, ok := node["properties"].(map[string]interface{}); ok {
+		for name, child := range properties {
+			childPath := name
+			if path != "" {
+				childPath = path + "." + name
+			}
+			if childMap, ok := child.(map[string]interface{}); ok && traversal.matchesSubtree(childPath, childMap) {
+				return true
+			}
+		}
+	}
+	if items, ok := node["items"].(map[string]interface{}); ok && traversal.matchesSubtree(path, items) {
+		return true
+	}
+	if additionalProperties, ok := node["additionalProperties"].(map[string]interface{}); ok && traversal.matchesSubtree(path, additionalProperties) {
+		return true
+	}
+	return false
+}
+
+func renderJSON(out io.Writer, root map[string]interface{}) error {
+	encoded, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(out, string(encoded))
+	return err
+}
+
+func renderYAML(out io.Writer, root map[string]interface{}) error {
+	encoded, err := yaml.Marshal(root)
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(encoded)
+	return err
+}
+
+func renderJSONPath(out io.Writer, root map[string]interface{}, expr string) error {
+	if expr == "" {
+		return fmt.Errorf("--output=jsonpath requires an expression, e.g. --output=jsonpath={.properties.spec.description}")
+	}
+	jp := jsonpath.New("explain").AllowMissingKeys(true)
+	if err := jp.Parse(expr); err != nil {
+		return fmt.Errorf("invalid jsonpath expression %q: %w", expr, err)
+	}
+	return jp.Execute(out, root)
+}
+
+// markdownRow is one line of the field table emitted by renderMarkdown.
+type markdownRow struct {
+	path        string
+	fieldType   string
+	required    bool
+	description string
+}
+
+func renderMarkdown(out io.Writer, fieldsPath []string, root map[string]interface{}) error {
+	rows := flattenMarkdownRows(strings.Join(fieldsPath, "."), root, false)
+
+	fmt.Fprintln(out, "| Field | Type | Required | Description |")
+	fmt.Fprintln(out, "|---|---|---|---|")
+	for _, row := range rows {
+		description := strings.ReplaceAll(row.description, "\n", " ")
+		fmt.Fprintf(out, "| %s | %s | %t | %s |\n", row.path, row.fieldType, row.required, description)
+	}
+	return nil
+}
+
+func flattenMarkdownRows(path string, node map[string]interface{}, required bool) []markdownRow {
+	fieldType, _ := node["type"].(string)
+	description, _ := node["description"].(string)
+	rows := []markdownRow{{path: path, fieldType: fieldType, required: required, description: description}}
+
+	properties, _ := node["properties"].(map[string]interface{})
+	requiredFields := map[string]bool{}
+	if names, ok := node["required"].([]string); ok {
+		for _, name := range names {
+			requiredFields[name] = true
+		}
+	}
+
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		childPath := name
+		if path != "" {
+			childPath = path + "." + name
+		}
+		child, _ := properties[name].(map[string]interface{})
+		rows = append(rows, flattenMarkdownRows(childPath, child, requiredFields[name])...)
+	}
+	return rows
+}
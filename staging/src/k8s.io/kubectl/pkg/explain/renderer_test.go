@@ -0,0 +1,209 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package explain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/kube-openapi/pkg/util/proto"
+)
+
+// podSpecLikeSchema builds a small, two-level *proto.Kind roughly shaped
+// like a real resource's spec: a required primitive field, an optional
+// primitive field, and a nested object field with its own primitive field.
+func podSpecLikeSchema() *proto.Kind {
+	return &proto.Kind{
+		BaseSchema:     proto.BaseSchema{Description: "spec description"},
+		FieldOrder:     []string{"name", "optional", "nested"},
+		RequiredFields: []string{"name"},
+		Fields: map[string]proto.Schema{
+			"name":     &proto.Primitive{BaseSchema: proto.BaseSchema{Description: "the name"}, Type: "string"},
+			"optional": &proto.Primitive{BaseSchema: proto.BaseSchema{Description: "an optional field"}, Type: "boolean"},
+			"nested": &proto.Kind{
+				BaseSchema: proto.BaseSchema{Description: "a nested object"},
+				FieldOrder: []string{"value"},
+				Fields: map[string]proto.Schema{
+					"value": &proto.Primitive{BaseSchema: proto.BaseSchema{Description: "the nested value"}, Type: "integer"},
+				},
+			},
+		},
+	}
+}
+
+func TestSchemaToMap(t *testing.T) {
+	t.Run("depth 1 expands one level but not nested children", func(t *testing.T) {
+		root := schemaToMap(podSpecLikeSchema(), "", 1, TraversalOptions{})
+
+		properties, ok := root["properties"].(map[string]interface{})
+		if !ok {
+			t.Fatalf("properties = %v, want a map", root["properties"])
+		}
+		nested, ok := properties["nested"].(map[string]interface{})
+		if !ok {
+			t.Fatalf("properties[nested] = %v, want a map", properties["nested"])
+		}
+		if _, ok := nested["properties"]; ok {
+			t.Errorf("nested.properties should not be expanded at depth 1, got %v", nested["properties"])
+		}
+	})
+
+	t.Run("unbounded depth expands nested children", func(t *testing.T) {
+		root := schemaToMap(podSpecLikeSchema(), "", -1, TraversalOptions{})
+
+		properties := root["properties"].(map[string]interface{})
+		nested := properties["nested"].(map[string]interface{})
+		nestedProperties, ok := nested["properties"].(map[string]interface{})
+		if !ok {
+			t.Fatalf("nested.properties = %v, want a map", nested["properties"])
+		}
+		if _, ok := nestedProperties["value"]; !ok {
+			t.Errorf("nested.properties = %v, want it to contain \"value\"", nestedProperties)
+		}
+	})
+
+	t.Run("required-only omits non-required fields", func(t *testing.T) {
+		root := schemaToMap(podSpecLikeSchema(), "", 1, TraversalOptions{RequiredOnly: true})
+
+		properties := root["properties"].(map[string]interface{})
+		if _, ok := properties["optional"]; ok {
+			t.Errorf("properties = %v, want \"optional\" omitted under RequiredOnly", properties)
+		}
+		if _, ok := properties["name"]; !ok {
+			t.Errorf("properties = %v, want \"name\" present under RequiredOnly", properties)
+		}
+		if required, _ := root["required"].([]string); !reflect.DeepEqual(required, []string{"name"}) {
+			t.Errorf("required = %v, want [name]", required)
+		}
+	})
+
+	t.Run("cyclic schema terminates instead of recursing forever", func(t *testing.T) {
+		// selfRef models a CRD's embedded JSONSchemaProps: a Kind whose
+		// own field resolves back to itself, the way
+		// JSONSchemaProps.properties nests more JSONSchemaProps values.
+		selfRef := &proto.Kind{
+			BaseSchema: proto.BaseSchema{Description: "a self-referential node"},
+			FieldOrder: []string{"nested"},
+		}
+		selfRef.Fields = map[string]proto.Schema{"nested": selfRef}
+
+		done := make(chan map[string]interface{}, 1)
+		go func() {
+			done <- schemaToMap(selfRef, "", -1, TraversalOptions{})
+		}()
+
+		select {
+		case root := <-done:
+			if root["type"] != "object" {
+				t.Errorf("root[type] = %v, want %q", root["type"], "object")
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("schemaToMap did not terminate on a cyclic schema")
+		}
+	})
+
+	t.Run("fields filter keeps only matching subpaths", func(t *testing.T) {
+		root := schemaToMap(podSpecLikeSchema(), "", -1, TraversalOptions{Fields: []string{"nested.value"}})
+
+		properties := root["properties"].(map[string]interface{})
+		if _, ok := properties["name"]; ok {
+			t.Errorf("properties = %v, want \"name\" filtered out", properties)
+		}
+		nested, ok := properties["nested"].(map[string]interface{})
+		if !ok {
+			t.Fatalf("properties[nested] = %v, want a map kept because its descendant matches", properties["nested"])
+		}
+		nestedProperties := nested["properties"].(map[string]interface{})
+		if _, ok := nestedProperties["value"]; !ok {
+			t.Errorf("nested.properties = %v, want \"value\" present", nestedProperties)
+		}
+	})
+}
+
+func TestMatchesSubtree(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields []string
+		path   string
+		node   map[string]interface{}
+		want   bool
+	}{
+		{
+			name:   "no fields filter matches everything",
+			fields: nil,
+			path:   "spec.whatever",
+			node:   map[string]interface{}{},
+			want:   true,
+		},
+		{
+			name:   "direct path match",
+			fields: []string{"spec.name"},
+			path:   "spec.name",
+			node:   map[string]interface{}{},
+			want:   true,
+		},
+		{
+			name:   "descendant match via properties",
+			fields: []string{"spec.nested.value"},
+			path:   "spec",
+			node: map[string]interface{}{
+				"properties": map[string]interface{}{
+					"nested": map[string]interface{}{
+						"properties": map[string]interface{}{
+							"value": map[string]interface{}{},
+						},
+					},
+				},
+			},
+			want: true,
+		},
+		{
+			name:   "descendant match via items",
+			fields: []string{"spec.containers.image"},
+			path:   "spec.containers",
+			node: map[string]interface{}{
+				"items": map[string]interface{}{
+					"properties": map[string]interface{}{
+						"image": map[string]interface{}{},
+					},
+				},
+			},
+			want: true,
+		},
+		{
+			name:   "no match anywhere in subtree",
+			fields: []string{"status.phase"},
+			path:   "spec",
+			node: map[string]interface{}{
+				"properties": map[string]interface{}{
+					"name": map[string]interface{}{},
+				},
+			},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traversal := TraversalOptions{Fields: tt.fields}
+			if got := traversal.matchesSubtree(tt.path, tt.node); got != tt.want {
+				t.Errorf("matchesSubtree(%q, %v) = %v, want %v", tt.path, tt.node, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package explain
+
+import "path/filepath"
+
+// TraversalOptions configures how deep field explanations recurse and which
+// fields are included. It is shared by the v1 renderers (RenderPlaintext and
+// Render) so --depth, --fields, and --required-only behave the same in
+// plaintext and structured output. The v2 (OpenAPI v3) path accepts only the
+// default options (see IsDefault).
+type TraversalOptions struct {
+	// Depth limits how many levels of nested fields are printed below the
+	// resolved field path. 1 (the default) preserves the historical
+	// behavior of printing one level of sub-fields; a negative value means
+	// unbounded.
+	Depth int
+
+	// Fields, when non-empty, restricts output to subpaths matching one of
+	// these glob patterns (e.g. "spec.containers*"). A node is kept if its
+	// own path matches, or if any of its descendants would match.
+	Fields []string
+
+	// RequiredOnly, when true, omits fields that the schema doesn't mark as
+	// required.
+	RequiredOnly bool
+}
+
+// Recursive reports whether these options are equivalent to the legacy
+// all-levels --recursive flag. It bridges TraversalOptions into renderers
+// that have not yet adopted a depth-aware implementation and only know a
+// plain "print everything" boolean.
+func (t TraversalOptions) Recursive() bool {
+	return t.Depth < 0
+}
+
+// IsDefault reports whether these options are exactly the historical
+// one-level-deep, unfiltered behavior, i.e. a caller that cannot honor
+// Depth/Fields/RequiredOnly at all (such as the v3 renderer) can still
+// safely proceed.
+func (t TraversalOptions) IsDefault() bool {
+	return t.Depth == 1 && len(t.Fields) == 0 && !t.RequiredOnly
+}
+
+// MatchesField reports whether path should be printed given Fields. An
+// empty Fields list matches everything.
+func (t TraversalOptions) MatchesField(path string) bool {
+	if len(t.Fields) == 0 {
+		return true
+	}
+	for _, pattern := range t.Fields {
+		if ok, _ := filepath.Match(pattern, path); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// nextDepth decrements a depth budget, leaving an unbounded (negative)
+// budget unchanged.
+func nextDepth(depth int) int {
+	if depth < 0 {
+		return depth
+	}
+	return depth - 1
+}